@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/wildskymedia/jiraToClubhouseCLI/jira"
+)
+
+// attachmentDownloadConcurrency caps how many attachments are downloaded
+// from Jira (and re-uploaded to Shortcut) at once, so a large project
+// doesn't serialize hundreds of HTTP round-trips.
+const attachmentDownloadConcurrency = 4
+
+const shortcutFilesURL = "https://api.app.shortcut.com/api/v3/files"
+
+// AttachmentMigrator downloads a JiraItem's attachments from a live Jira
+// instance and re-uploads them to Shortcut, returning the resulting
+// Shortcut file IDs so they can be attached to the created story.
+type AttachmentMigrator struct {
+	jiraConfig       jira.Config
+	shortcutAPIToken string
+	shortcutClient   *http.Client
+}
+
+// NewAttachmentMigrator builds an AttachmentMigrator that authenticates
+// against Jira with jiraConfig (the same credentials used by the live
+// ingestion mode) and against Shortcut with shortcutAPIToken.
+func NewAttachmentMigrator(jiraConfig jira.Config, shortcutAPIToken string) *AttachmentMigrator {
+	return &AttachmentMigrator{
+		jiraConfig:       jiraConfig,
+		shortcutAPIToken: shortcutAPIToken,
+		shortcutClient:   &http.Client{},
+	}
+}
+
+// Migrate downloads every attachment on item and re-uploads it to
+// Shortcut, bounded by attachmentDownloadConcurrency, returning the
+// resulting Shortcut file IDs in the same order as item.Attachments.
+func (m *AttachmentMigrator) Migrate(item *JiraItem) ([]int64, error) {
+	if len(item.Attachments) == 0 {
+		return nil, nil
+	}
+
+	client, err := jira.NewClient(m.jiraConfig)
+	if err != nil {
+		return nil, fmt.Errorf("attachments: %w", err)
+	}
+
+	fileIDs := make([]int64, len(item.Attachments))
+	sem := make(chan struct{}, attachmentDownloadConcurrency)
+	g, ctx := errgroup.WithContext(context.Background())
+
+	for i, attachment := range item.Attachments {
+		i, attachment := i, attachment
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+
+			fileID, err := m.migrateOne(client, attachment)
+			if err != nil {
+				return fmt.Errorf("attachment %s: %w", attachment.Name, err)
+			}
+			fileIDs[i] = fileID
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return fileIDs, nil
+}
+
+func (m *AttachmentMigrator) migrateOne(client *jira.Client, attachment JiraAttachment) (int64, error) {
+	resp, err := client.DownloadAttachment(m.attachmentURL(attachment))
+	if err != nil {
+		return 0, fmt.Errorf("downloading: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("downloading: jira returned %s", resp.Status)
+	}
+
+	fileID, err := m.uploadToShortcut(attachment.Name, resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("uploading to Shortcut: %w", err)
+	}
+
+	return fileID, nil
+}
+
+// attachmentURL builds the Jira attachment content URL. Jira's XML export
+// only gives us the attachment's id and name, not a direct link. The name
+// is escaped as a path segment: an unescaped "#" or "?" would otherwise be
+// parsed as a URL fragment/query, silently truncating the path before the
+// request is even sent.
+func (m *AttachmentMigrator) attachmentURL(attachment JiraAttachment) string {
+	base := strings.TrimRight(m.jiraConfig.BaseURL, "/")
+	return base + "/secure/attachment/" + attachment.ID + "/" + url.PathEscape(attachment.Name)
+}
+
+type shortcutFile struct {
+	ID int64 `json:"id"`
+}
+
+// uploadToShortcut uploads content as name via Shortcut's file-upload
+// endpoint and returns the resulting file's ID.
+func (m *AttachmentMigrator) uploadToShortcut(name string, content io.Reader) (int64, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file0", name)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, shortcutFilesURL, body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Shortcut-Token", m.shortcutAPIToken)
+
+	resp, err := m.shortcutClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return 0, fmt.Errorf("shortcut returned %s", resp.Status)
+	}
+
+	var files []shortcutFile
+	if err := json.NewDecoder(resp.Body).Decode(&files); err != nil {
+		return 0, fmt.Errorf("decoding response: %w", err)
+	}
+	if len(files) == 0 {
+		return 0, fmt.Errorf("shortcut returned no files")
+	}
+
+	return files[0].ID, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import "time"
+
+// ClubHouseData is the fully translated payload GetDataForClubhouse
+// produces: what to create, what to update (in --sync mode), and what
+// --dry-run found when diffing against what's already in Shortcut.
+type ClubHouseData struct {
+	Epics   []ClubHouseCreateEpic  `json:"epics"`
+	Stories []ClubHouseCreateStory `json:"stories"`
+
+	Updates []ClubHouseUpdateStory `json:"-"`
+	Diffs   []StoryDiff            `json:"-"`
+}
+
+// ClubHouseCreateEpic is a Shortcut epic to be created from a Jira Epic.
+type ClubHouseCreateEpic struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	key string
+}
+
+// ClubHouseCreateTask is a Shortcut checklist task to be created from a
+// Jira Sub-task, nested under its parent story.
+type ClubHouseCreateTask struct {
+	Description string `json:"description"`
+	Complete    bool   `json:"complete"`
+
+	parent string
+}
+
+// ClubHouseCreateStory is a Shortcut story to be created from a Jira item.
+type ClubHouseCreateStory struct {
+	Name          string                   `json:"name"`
+	Description   string                   `json:"description"`
+	StoryType     string                   `json:"story_type"`
+	WorkflowState int64                    `json:"workflow_state_id"`
+	OwnerIDs      []string                 `json:"owner_ids"`
+	RequestedBy   string                   `json:"requested_by_id"`
+	Estimate      int64                    `json:"estimate"`
+	GroupID       string                   `json:"group_id"`
+	ExternalID    string                   `json:"external_id"`
+	ExternalLinks []string                 `json:"external_links"`
+	FileIDs       []int64                  `json:"file_ids"`
+	Labels        []ClubHouseCreateLabel   `json:"labels"`
+	Comments      []ClubHouseCreateComment `json:"comments"`
+	Tasks         []ClubHouseCreateTask    `json:"tasks"`
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+	StartedAt     time.Time                `json:"started_at"`
+	CompletedAt   time.Time                `json:"completed_at"`
+
+	key      string
+	epicLink string
+}
+
+// ClubHouseCreateComment is a Shortcut comment to be created on a story.
+type ClubHouseCreateComment struct {
+	Text      string    `json:"text"`
+	Author    string    `json:"author_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ClubHouseCreateLabel is a Shortcut label to be applied to a story.
+type ClubHouseCreateLabel struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Custom field targets a CustomFieldRule can route a Jira custom field's
+// value to.
+const (
+	CustomFieldTargetLabel    = "label"
+	CustomFieldTargetEstimate = "estimate"
+	CustomFieldTargetEpicLink = "epic_link"
+)
+
+// CustomFieldRule declares, in mapping YAML, how one Jira custom field
+// should be translated onto a ClubHouseCreateStory. For example:
+//
+//	custom_fields:
+//	  - jira_field: Team
+//	    target: label
+//	    prefix: "team-"
+//	  - jira_field: Epic Name
+//	    target: epic_name
+//	  - jira_field: Story Points
+//	    target: estimate
+//	    transform: round
+type CustomFieldRule struct {
+	JiraField string `yaml:"jira_field"`
+	Target    string `yaml:"target"`
+	Prefix    string `yaml:"prefix,omitempty"`
+	Transform string `yaml:"transform,omitempty"`
+}
+
+// CustomFieldMapper applies a MappingConfig's custom_fields rules to a
+// JiraItem's CustomFields in a single table-driven pass, replacing the old
+// one-off GetEstimate/GetLastSprint/GetEpicLink lookups.
+type CustomFieldMapper struct {
+	rules map[string]CustomFieldRule
+}
+
+// NewCustomFieldMapper indexes mc's custom-field rules by Jira field name.
+func NewCustomFieldMapper(mc *MappingConfig) *CustomFieldMapper {
+	rules := make(map[string]CustomFieldRule, len(mc.CustomFields))
+	for _, rule := range mc.CustomFields {
+		rules[rule.JiraField] = rule
+	}
+	return &CustomFieldMapper{rules: rules}
+}
+
+// MappedFields is everything a pass over a JiraItem's custom fields
+// produced, ready to be merged into a ClubHouseCreateStory.
+type MappedFields struct {
+	Labels   []string
+	Estimate int64
+	EpicLink string
+
+	// DescriptionExtras holds "Field: value" lines for any mapped custom
+	// field with no dedicated target (e.g. Epic Name), to be appended to
+	// the story description as an appendix.
+	DescriptionExtras []string
+}
+
+// Apply walks item.CustomFields once, routing each one that has a matching
+// rule to its configured target.
+func (m *CustomFieldMapper) Apply(item *JiraItem) MappedFields {
+	var mapped MappedFields
+
+	for _, cf := range item.CustomFields {
+		rule, ok := m.rules[cf.FieldName]
+		if !ok || len(cf.FieldValues) == 0 {
+			continue
+		}
+
+		switch rule.Target {
+		case CustomFieldTargetLabel:
+			values := cf.FieldValues
+			if rule.Transform == "last" {
+				values = values[len(values)-1:]
+			}
+			for _, v := range values {
+				mapped.Labels = append(mapped.Labels, rule.Prefix+v)
+			}
+		case CustomFieldTargetEstimate:
+			if estimate, ok := applyNumericTransform(cf.FieldValues[0], rule.Transform); ok {
+				mapped.Estimate = estimate
+			}
+		case CustomFieldTargetEpicLink:
+			mapped.EpicLink = cf.FieldValues[0]
+		default:
+			mapped.DescriptionExtras = append(mapped.DescriptionExtras, fmt.Sprintf("%s: %s", cf.FieldName, cf.FieldValues[0]))
+		}
+	}
+
+	return mapped
+}
+
+// applyNumericTransform parses value as a float and applies transform to
+// it, defaulting to truncating towards zero (matching the prior hardcoded
+// Story Points behavior of the old GetEstimate's int64(i) conversion).
+func applyNumericTransform(value, transform string) (int64, bool) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	switch transform {
+	case "floor":
+		return int64(math.Floor(f)), true
+	case "ceil":
+		return int64(math.Ceil(f)), true
+	case "round":
+		return int64(math.Round(f)), true
+	default:
+		return int64(f), true
+	}
+}
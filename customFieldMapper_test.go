@@ -0,0 +1,82 @@
+package main
+
+import "testing"
+
+func TestCustomFieldMapperApply(t *testing.T) {
+	mc := &MappingConfig{
+		CustomFields: []CustomFieldRule{
+			{JiraField: "Team", Target: CustomFieldTargetLabel, Prefix: "team-"},
+			{JiraField: "Fix Version", Target: CustomFieldTargetLabel, Transform: "last"},
+			{JiraField: "Story Points", Target: CustomFieldTargetEstimate, Transform: "ceil"},
+			{JiraField: "Epic Link", Target: CustomFieldTargetEpicLink},
+			{JiraField: "Epic Name", Target: ""},
+		},
+	}
+	mapper := NewCustomFieldMapper(mc)
+
+	item := &JiraItem{
+		CustomFields: []JiraCustomField{
+			{FieldName: "Team", FieldValues: []string{"Platform"}},
+			{FieldName: "Fix Version", FieldValues: []string{"1.0", "1.1", "1.2"}},
+			{FieldName: "Story Points", FieldValues: []string{"2.2"}},
+			{FieldName: "Epic Link", FieldValues: []string{"PROJ-100"}},
+			{FieldName: "Epic Name", FieldValues: []string{"Big Epic"}},
+			{FieldName: "Unmapped Field", FieldValues: []string{"ignored"}},
+			{FieldName: "Empty Field", FieldValues: nil},
+		},
+	}
+
+	mapped := mapper.Apply(item)
+
+	if got, want := mapped.Labels, []string{"team-Platform", "1.2"}; !stringSlicesEqual(got, want) {
+		t.Errorf("Labels = %v, want %v", got, want)
+	}
+	if mapped.Estimate != 3 {
+		t.Errorf("Estimate = %d, want 3 (ceil of 2.2)", mapped.Estimate)
+	}
+	if mapped.EpicLink != "PROJ-100" {
+		t.Errorf("EpicLink = %q, want %q", mapped.EpicLink, "PROJ-100")
+	}
+	if got, want := mapped.DescriptionExtras, []string{"Epic Name: Big Epic"}; !stringSlicesEqual(got, want) {
+		t.Errorf("DescriptionExtras = %v, want %v", got, want)
+	}
+}
+
+func TestApplyNumericTransform(t *testing.T) {
+	cases := []struct {
+		value     string
+		transform string
+		want      int64
+		ok        bool
+	}{
+		{value: "2.2", transform: "round", want: 2, ok: true},
+		{value: "2.5", transform: "round", want: 3, ok: true},
+		{value: "2.2", transform: "floor", want: 2, ok: true},
+		{value: "2.2", transform: "ceil", want: 3, ok: true},
+		{value: "2.6", transform: "", want: 2, ok: true},
+		{value: "not a number", transform: "round", want: 0, ok: false},
+	}
+
+	for _, tc := range cases {
+		got, ok := applyNumericTransform(tc.value, tc.transform)
+		if ok != tc.ok {
+			t.Errorf("applyNumericTransform(%q, %q) ok = %v, want %v", tc.value, tc.transform, ok, tc.ok)
+			continue
+		}
+		if ok && got != tc.want {
+			t.Errorf("applyNumericTransform(%q, %q) = %d, want %d", tc.value, tc.transform, got, tc.want)
+		}
+	}
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,396 @@
+// Package jira provides a minimal client for pulling issues directly out of a
+// live Jira instance via the REST API, as an alternative to the XML export
+// that the rest of jiraToClubhouseCLI consumes.
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuthMode selects how requests to the Jira REST API are authenticated.
+type AuthMode string
+
+const (
+	// AuthBasic signs requests with a Jira username and password (or API token).
+	AuthBasic AuthMode = "basic"
+	// AuthOAuth signs requests with OAuth 1.0a, RSA-SHA1, as required by Jira
+	// Application Links.
+	AuthOAuth AuthMode = "oauth"
+)
+
+// searchPageSize is the number of issues requested per page of /rest/api/2/search.
+const searchPageSize = 50
+
+// Config holds the connection details for a live Jira instance.
+type Config struct {
+	BaseURL  string
+	AuthMode AuthMode
+
+	// Basic auth
+	Username string
+	Password string
+
+	// OAuth 1.0a
+	ConsumerKey    string
+	PrivateKeyPath string
+	Token          string
+	TokenSecret    string
+}
+
+// Client talks to the Jira REST API on behalf of a single Config.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	signer     requestSigner
+}
+
+// requestSigner authenticates an outgoing *http.Request in place.
+type requestSigner interface {
+	Sign(req *http.Request) error
+}
+
+// NewClient builds a Client for the given Config, validating that the fields
+// required by the chosen AuthMode are present.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("jira: BaseURL is required")
+	}
+
+	var signer requestSigner
+	switch cfg.AuthMode {
+	case AuthBasic:
+		if cfg.Username == "" || cfg.Password == "" {
+			return nil, fmt.Errorf("jira: basic auth requires Username and Password")
+		}
+		signer = basicAuthSigner{username: cfg.Username, password: cfg.Password}
+	case AuthOAuth:
+		s, err := newOAuth1Signer(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("jira: %w", err)
+		}
+		signer = s
+	default:
+		return nil, fmt.Errorf("jira: unknown auth mode %q", cfg.AuthMode)
+	}
+
+	return &Client{
+		config:     cfg,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		signer:     signer,
+	}, nil
+}
+
+// searchResponse mirrors the fields of /rest/api/2/search that we care about.
+type searchResponse struct {
+	StartAt    int               `json:"startAt"`
+	MaxResults int               `json:"maxResults"`
+	Total      int               `json:"total"`
+	Issues     []RawIssue        `json:"issues"`
+	Names      map[string]string `json:"names"`
+}
+
+// RawIssue is a single issue as returned by the Jira REST API.
+type RawIssue struct {
+	Key       string      `json:"key"`
+	Fields    IssueFields `json:"fields"`
+	Changelog Changelog   `json:"changelog"`
+
+	// CustomFields holds every customfield_* value Jira returned for this
+	// issue, resolved to its human-readable name via ?expand=names.
+	// searchPage populates this after decoding, since resolving an id to a
+	// name needs the search response's top-level "names" map, not just this
+	// issue's own fields.
+	CustomFields []CustomField
+}
+
+// CustomField is one customfield_* entry from a Jira issue, resolved to the
+// human-readable field name that CustomFieldMapper's rules are keyed by
+// (e.g. "Story Points", "Epic Link").
+type CustomField struct {
+	Name   string
+	Values []string
+}
+
+// Changelog is the ?expand=changelog portion of a Jira issue: the full
+// history of field transitions, each possibly touching several fields at
+// once.
+type Changelog struct {
+	Histories []ChangelogHistory `json:"histories"`
+}
+
+// ChangelogHistory is one changelog entry: one author, at one time,
+// changing one or more fields.
+type ChangelogHistory struct {
+	Author struct {
+		Name string `json:"name"`
+	} `json:"author"`
+	Created string                 `json:"created"`
+	Items   []ChangelogHistoryItem `json:"items"`
+}
+
+// ChangelogHistoryItem is a single field transition within a
+// ChangelogHistory.
+type ChangelogHistoryItem struct {
+	Field      string `json:"field"`
+	FromString string `json:"fromString"`
+	ToString   string `json:"toString"`
+}
+
+// IssueFields is the subset of a Jira issue's fields that jiraToClubhouseCLI
+// translates into a JiraItem.
+type IssueFields struct {
+	Summary     string          `json:"summary"`
+	Description string          `json:"description"`
+	Status      struct {
+		Name string `json:"name"`
+	} `json:"status"`
+	IssueType struct {
+		Name string `json:"name"`
+	} `json:"issuetype"`
+	Resolution *struct {
+		Name string `json:"name"`
+	} `json:"resolution"`
+	Assignee *struct {
+		Name string `json:"name"`
+	} `json:"assignee"`
+	Reporter *struct {
+		Name string `json:"name"`
+	} `json:"reporter"`
+	Created  string   `json:"created"`
+	Updated  string   `json:"updated"`
+	Resolutiondate string `json:"resolutiondate"`
+	Labels   []string `json:"labels"`
+	Components []struct {
+		Name string `json:"name"`
+	} `json:"components"`
+	Parent *struct {
+		Key string `json:"key"`
+	} `json:"parent"`
+	Comment struct {
+		Comments []struct {
+			Author struct {
+				Name string `json:"name"`
+			} `json:"author"`
+			Body    string `json:"body"`
+			Created string `json:"created"`
+			ID      string `json:"id"`
+		} `json:"comments"`
+	} `json:"comment"`
+	Attachment []struct {
+		ID       string      `json:"id"`
+		Filename string      `json:"filename"`
+		Size     json.Number `json:"size"`
+		Author   struct {
+			Name string `json:"name"`
+		} `json:"author"`
+		Created string `json:"created"`
+		Content string `json:"content"`
+	} `json:"attachment"`
+
+	// rawCustomFields holds whatever customfield_* entries UnmarshalJSON
+	// found that the fixed fields above don't already claim, since which
+	// custom fields exist is per-Jira-instance configuration. Resolved to
+	// human-readable names (and exposed on RawIssue.CustomFields) once
+	// searchPage has the search response's "names" map in hand.
+	rawCustomFields map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes the fixed fields above as usual, then makes a
+// second pass over the same bytes to capture any customfield_* entry so it
+// isn't silently dropped.
+func (f *IssueFields) UnmarshalJSON(data []byte) error {
+	type alias IssueFields
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*f = IssueFields(a)
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	f.rawCustomFields = make(map[string]json.RawMessage)
+	for key, value := range raw {
+		if strings.HasPrefix(key, "customfield_") {
+			f.rawCustomFields[key] = value
+		}
+	}
+
+	return nil
+}
+
+// SearchIssues pages through /rest/api/2/search for the given JQL and returns
+// every matching issue. It is safe to use for incremental syncs, e.g. a JQL
+// of "updated >= -1d".
+func (c *Client) SearchIssues(jql string) ([]RawIssue, error) {
+	var all []RawIssue
+	startAt := 0
+
+	for {
+		page, total, err := c.searchPage(jql, startAt)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+
+		startAt += len(page)
+		if len(page) == 0 || startAt >= total {
+			break
+		}
+	}
+
+	return all, nil
+}
+
+func (c *Client) searchPage(jql string, startAt int) ([]RawIssue, int, error) {
+	req, err := http.NewRequest(http.MethodGet, c.config.BaseURL+"/rest/api/2/search", nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q := req.URL.Query()
+	q.Set("jql", jql)
+	q.Set("startAt", fmt.Sprintf("%d", startAt))
+	q.Set("maxResults", fmt.Sprintf("%d", searchPageSize))
+	q.Set("expand", "changelog,names")
+	req.URL.RawQuery = q.Encode()
+
+	if err := c.signer.Sign(req); err != nil {
+		return nil, 0, fmt.Errorf("jira: signing request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("jira: search returned %s", resp.Status)
+	}
+
+	var result searchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, 0, fmt.Errorf("jira: decoding search response: %w", err)
+	}
+
+	for i := range result.Issues {
+		result.Issues[i].CustomFields = resolveCustomFields(result.Issues[i].Fields.rawCustomFields, result.Names)
+	}
+
+	return result.Issues, result.Total, nil
+}
+
+// resolveCustomFields turns the raw customfield_NNNNN JSON values captured
+// by IssueFields.UnmarshalJSON into CustomFields, keyed by the
+// human-readable names the "names" expand returns, in a deterministic
+// (sorted by id) order. A custom field whose value can't be flattened to
+// strings (null, an empty array, ...) is dropped rather than emitted empty.
+func resolveCustomFields(raw map[string]json.RawMessage, names map[string]string) []CustomField {
+	ids := make([]string, 0, len(raw))
+	for id := range raw {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	fields := make([]CustomField, 0, len(ids))
+	for _, id := range ids {
+		values := flattenCustomFieldValue(raw[id])
+		if len(values) == 0 {
+			continue
+		}
+
+		name := names[id]
+		if name == "" {
+			name = id
+		}
+
+		fields = append(fields, CustomField{Name: name, Values: values})
+	}
+
+	return fields
+}
+
+// flattenCustomFieldValue reduces a customfield_* value - a plain string or
+// number, a single-select option ({"value": "..."} or {"name": "..."}), or
+// an array of any of those (multi-select, labels, fix versions) - down to
+// the []string shape CustomFieldMapper.Apply already expects from the XML
+// export's <customfieldvalues>.
+func flattenCustomFieldValue(raw json.RawMessage) []string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil
+		}
+		return []string{s}
+	}
+
+	var n float64
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return []string{strconv.FormatFloat(n, 'f', -1, 64)}
+	}
+
+	var option struct {
+		Value string `json:"value"`
+		Name  string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &option); err == nil {
+		if option.Value != "" {
+			return []string{option.Value}
+		}
+		if option.Name != "" {
+			return []string{option.Name}
+		}
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		var values []string
+		for _, item := range list {
+			values = append(values, flattenCustomFieldValue(item)...)
+		}
+		return values
+	}
+
+	return nil
+}
+
+// DownloadAttachment fetches an arbitrary Jira URL using the client's
+// configured authentication. It's used to pull attachment content for
+// migration into another system, since the XML export only carries
+// attachment metadata, never the file itself.
+func (c *Client) DownloadAttachment(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.signer.Sign(req); err != nil {
+		return nil, fmt.Errorf("jira: signing request: %w", err)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// basicAuthSigner authenticates requests with HTTP basic auth.
+type basicAuthSigner struct {
+	username string
+	password string
+}
+
+func (s basicAuthSigner) Sign(req *http.Request) error {
+	req.SetBasicAuth(s.username, s.password)
+	return nil
+}
@@ -0,0 +1,46 @@
+package jira
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPercentEncode(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "updated >= -1d", want: "updated%20%3E%3D%20-1d"},
+		{in: "abc123-._~", want: "abc123-._~"},
+		{in: "a b", want: "a%20b"},
+		{in: "a+b", want: "a%2Bb"},
+		{in: "", want: ""},
+	}
+
+	for _, tc := range cases {
+		if got := percentEncode(tc.in); got != tc.want {
+			t.Errorf("percentEncode(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestSignatureBaseStringEncodesSpaceAsPercent20(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://jira.example.com/rest/api/2/search?jql=updated+%3E%3D+-1d", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := signatureBaseString(req, map[string]string{"oauth_nonce": "abc123"})
+
+	// jql's value, "updated >= -1d", must appear RFC-3986-encoded (%20 for
+	// space), never form-encoded (+ for space) -- a Jira server
+	// reconstructing the base string per RFC 5849 section 3.6 would decode
+	// a "+" back to a literal space, producing a different signature.
+	if want := "jql%3Dupdated%2520%253E%253D%2520-1d"; !strings.Contains(base, want) {
+		t.Errorf("signatureBaseString() = %q, want it to contain %q", base, want)
+	}
+	if strings.Contains(base, "updated+") {
+		t.Errorf("signatureBaseString() = %q, contains form-encoded space (+)", base)
+	}
+}
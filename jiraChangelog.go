@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StatusCategoryStarted and StatusCategoryDone are the values a
+// MappingConfig's status_categories map uses to mark which Jira statuses
+// correspond to work starting and work finishing.
+const (
+	StatusCategoryStarted = "started"
+	StatusCategoryDone    = "done"
+)
+
+// ChangelogSummary is what CreateStory needs out of a JiraItem's changelog:
+// a synthesized, human-readable history section plus the first time the
+// item transitioned into a "started" and a "done" mapped status.
+type ChangelogSummary struct {
+	HistoryText string
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+}
+
+// SummarizeChangelog walks item.Changelog once, building a history section
+// for the description and finding the first status transition into each of
+// mc's "started" and "done" categories. It replaces the old ResolvedAtString
+// +/- 1 day heuristic for items that have changelog data (i.e. ones pulled
+// via the live Jira REST path); XML-imported items have no changelog and
+// get a zero ChangelogSummary.
+func (item *JiraItem) SummarizeChangelog(mc *MappingConfig) ChangelogSummary {
+	var summary ChangelogSummary
+	if len(item.Changelog) == 0 {
+		return summary
+	}
+
+	lines := make([]string, 0, len(item.Changelog))
+	for _, entry := range item.Changelog {
+		createdAt := ParseJiraRESTTimestamp(entry.CreatedAtString)
+		lines = append(lines, formatChangelogLine(entry, createdAt))
+
+		if entry.Field != "status" {
+			continue
+		}
+
+		switch mc.StatusCategories[entry.ToString] {
+		case StatusCategoryStarted:
+			if summary.StartedAt == nil {
+				t := createdAt
+				summary.StartedAt = &t
+			}
+		case StatusCategoryDone:
+			if summary.CompletedAt == nil {
+				t := createdAt
+				summary.CompletedAt = &t
+			}
+		}
+	}
+
+	summary.HistoryText = "\n\n**History**\n" + strings.Join(lines, "\n")
+	return summary
+}
+
+// formatChangelogLine renders a single entry as
+// "- 2024-01-03 alice: status Open → In Progress", or, for fields with no
+// "from" value, "- 2024-01-05 bob: assignee → carol".
+func formatChangelogLine(entry JiraChangelogEntry, createdAt time.Time) string {
+	transition := entry.Field
+	if entry.FromString != "" {
+		transition += " " + entry.FromString + " → " + entry.ToString
+	} else {
+		transition += " → " + entry.ToString
+	}
+	return fmt.Sprintf("- %s %s: %s", createdAt.Format("2006-01-02"), entry.Author, transition)
+}
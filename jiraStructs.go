@@ -3,7 +3,6 @@ package main
 import (
 	"encoding/xml"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
@@ -43,6 +42,20 @@ type JiraItem struct {
 	Comments     []JiraComment     `xml:"comments>comment"`
 	CustomFields []JiraCustomField `xml:"customfields>customfield"`
 	Component    []string          `xml:"component"`
+	Attachments  []JiraAttachment  `xml:"attachments>attachment"`
+	Worklogs     []JiraWorklog     `xml:"worklog>worklogs>worklog"`
+
+	// Changelog is only populated via the live Jira REST ingestion path
+	// (?expand=changelog); the XML export has no equivalent, so it is
+	// always empty for XML-imported items.
+	Changelog []JiraChangelogEntry
+
+	// FromLiveAPI is true when this item was pulled via the live Jira REST
+	// ingestion path (jiraLiveSource.go) rather than unmarshalled from an
+	// XML export. The two sources stamp CreatedAtString/UpdatedAtString/
+	// ResolvedAtString in different timestamp formats, so CreateStory and
+	// CreateEpic need to know which parser to use.
+	FromLiveAPI bool
 
 	epicLink string
 	UpdatedAtString string   		`xml:"updated"`
@@ -61,6 +74,66 @@ type JiraComment struct {
 	CreatedAtString string `xml:"created,attr"`
 	Comment         string `xml:",chardata"`
 	ID              string `xml:"id,attr"`
+
+	// FromLiveAPI mirrors JiraItem.FromLiveAPI: CreatedAtString is in the
+	// live Jira REST API's timestamp format for comments pulled via
+	// jiraLiveSource.go, rather than the XML export's RSS format.
+	FromLiveAPI bool
+}
+
+// JiraAttachment is a file attached to a Jira item. The XML export only
+// carries this metadata; the content itself has to be fetched live from
+// Jira, which is what AttachmentMigrator does.
+type JiraAttachment struct {
+	ID              string `xml:"id,attr"`
+	Name            string `xml:"name,attr"`
+	Size            string `xml:"size,attr"`
+	Author          string `xml:"author,attr"`
+	CreatedAtString string `xml:"created,attr"`
+}
+
+// JiraChangelogEntry is a single field transition from a Jira issue's
+// changelog (?expand=changelog on the REST issue endpoint): one field
+// changing from one value to another, by one author, at one time.
+type JiraChangelogEntry struct {
+	Author          string
+	CreatedAtString string
+	Field           string
+	FromString      string
+	ToString        string
+}
+
+// JiraWorklog is a single work log entry on a Jira item. Shortcut has no
+// native time-tracking field, so these are migrated to comments instead.
+type JiraWorklog struct {
+	ID              string `xml:"id,attr"`
+	Author          string `xml:"author,attr"`
+	StartedAtString string `xml:"startDate,attr"`
+	TimeSpent       string `xml:"timeSpent,attr"`
+	Comment         string `xml:"comment,attr"`
+}
+
+// CreateComment converts a JiraWorklog into a ClubHouseCreateComment, since
+// Shortcut has no native time-tracking field to migrate it into instead.
+func (worklog *JiraWorklog) CreateComment(userMaps []userMap) ClubHouseCreateComment {
+	author := MapUser(userMaps, worklog.Author)
+	if author == "" {
+		author = MapUser(userMaps, "matt.messinger")
+	}
+
+	duration := strings.ReplaceAll(worklog.TimeSpent, " ", "")
+	startedAt := ParseJiraTimeStamp(worklog.StartedAtString)
+
+	text := fmt.Sprintf("[worklog: %s by @%s on %s]", duration, worklog.Author, startedAt.Format("2006-01-02"))
+	if worklog.Comment != "" {
+		text += "\n" + sanitize.HTML(worklog.Comment)
+	}
+
+	return ClubHouseCreateComment{
+		Text:      text,
+		CreatedAt: startedAt,
+		Author:    author,
+	}
 }
 
 func GetUserInfo(userMaps []userMap, jiraUsername string) (CHProjectID int, CHID string) {
@@ -73,7 +146,7 @@ func GetUserInfo(userMaps []userMap, jiraUsername string) (CHProjectID int, CHID
 }
 
 //GetDataForClubhouse will take the data from the XML and translate it into a format for sending to Clubhouse
-func (je *JiraExport) GetDataForClubhouse(userMaps []userMap) ClubHouseData {
+func (je *JiraExport) GetDataForClubhouse(userMaps []userMap, mc *MappingConfig, migrator *AttachmentMigrator, shortcut *ShortcutClient) ClubHouseData {
 	epics := []JiraItem{}
 	tasks := []JiraItem{}
 	stories := []JiraItem{}
@@ -105,8 +178,41 @@ func (je *JiraExport) GetDataForClubhouse(userMaps []userMap) ClubHouseData {
 		chTasks = append(chTasks, item.CreateTask())
 	}
 
+	var chUpdates []ClubHouseUpdateStory
+	var diffs []StoryDiff
+
 	for _, item := range stories {
-		chStories = append(chStories, item.CreateStory(userMaps))
+		story := item.CreateStory(userMaps, mc, migrator)
+
+		// Without a ShortcutClient (the default, plain import) every story
+		// is created, same as before --dry-run/--sync existed.
+		if shortcut == nil {
+			chStories = append(chStories, story)
+			continue
+		}
+
+		existing, err := shortcut.FindStoryByExternalID(story.ExternalID)
+		if err != nil {
+			fmt.Println("[ShortcutClient] failed to look up", story.ExternalID, ":", err)
+			chStories = append(chStories, story)
+			continue
+		}
+
+		diff := DiffStory(existing, story)
+		diffs = append(diffs, diff)
+
+		// --dry-run only ever reports what DiffStory found; it must never
+		// hand back anything that would create or update a Shortcut story.
+		if shortcut.DryRun {
+			continue
+		}
+
+		switch diff.Action {
+		case StoryDiffCreate:
+			chStories = append(chStories, story)
+		case StoryDiffUpdate:
+			chUpdates = append(chUpdates, NewUpdateStory(existing.ID, story))
+		}
 	}
 
 	// storyMap is used to link the JiraItem's key to its index in the chStories slice. This is then used to assign subtasks properly
@@ -119,12 +225,12 @@ func (je *JiraExport) GetDataForClubhouse(userMaps []userMap) ClubHouseData {
 		chStories[storyMap[task.parent]].Tasks = append(chStories[storyMap[task.parent]].Tasks, task)
 	}
 
-	return ClubHouseData{Epics: chEpics, Stories: chStories}
+	return ClubHouseData{Epics: chEpics, Stories: chStories, Updates: chUpdates, Diffs: diffs}
 }
 
 // CreateEpic returns a ClubHouseCreateEpic from the JiraItem
 func (item *JiraItem) CreateEpic() ClubHouseCreateEpic {
-	return ClubHouseCreateEpic{Description: sanitize.HTML(item.Description), Name: sanitize.HTML(item.Summary), key: item.Key, CreatedAt: ParseJiraTimeStamp(item.CreatedAtString)}
+	return ClubHouseCreateEpic{Description: sanitize.HTML(item.Description), Name: sanitize.HTML(item.Summary), key: item.Key, CreatedAt: parseItemTimestamp(item.CreatedAtString, item.FromLiveAPI, 0)}
 }
 
 // CreateTask returns a task if the item is a Jira Sub-task
@@ -133,7 +239,7 @@ func (item *JiraItem) CreateTask() ClubHouseCreateTask {
 }
 
 // CreateStory returns a ClubHouseCreateStory from the JiraItem
-func (item *JiraItem) CreateStory(userMaps []userMap) ClubHouseCreateStory {
+func (item *JiraItem) CreateStory(userMaps []userMap, mc *MappingConfig, migrator *AttachmentMigrator) ClubHouseCreateStory {
 	// fmt.Println("assignee: ", item.Assignee, "reporter: ", item.Reporter)
 	// return ClubHouseCreateStory{}
 
@@ -142,27 +248,25 @@ func (item *JiraItem) CreateStory(userMaps []userMap) ClubHouseCreateStory {
 		comments = append(comments, c.CreateComment(userMaps))
 	}
 
+	// Shortcut has no native time-tracking field, so worklogs become
+	// prefixed comments instead.
+	for _, wl := range item.Worklogs {
+		comments = append(comments, wl.CreateComment(userMaps))
+	}
+
 	labels := []ClubHouseCreateLabel{}
 	for _, label := range item.Labels {
 		labels = append(labels, ClubHouseCreateLabel{Name: strings.ToLower(label)})
 	}
 
-	// Add a label for tracking jira sprints in 
-	lastSprint := item.GetLastSprint()
+	// Walk the custom fields once, routing each one to whatever the mapping
+	// config says it's for (labels, estimate, epic link, ...).
+	mapped := NewCustomFieldMapper(mc).Apply(item)
 
-	if lastSprint != "" {
-		labels = append(labels, ClubHouseCreateLabel{Name: lastSprint})
+	for _, label := range mapped.Labels {
+		labels = append(labels, ClubHouseCreateLabel{Name: label})
 	}
 
-	// Option to create a label for every sprint the jira item was in
-	// for _, cf := range item.CustomFields {
-	// 	if cf.FieldName == "Sprint" && len(cf.FieldValues) > 0 {
-	// 		for _, sprints := range cf.FieldValues {
-	// 			labels = append(labels, ClubHouseCreateLabel{Name: sprints})
-	// 		}
-	// 	}
-	// }
-
 	// Adding a label for components added to the jira tickets
 	for _, component := range item.Component {
 		labels = append(labels, ClubHouseCreateLabel{Name: component})
@@ -194,37 +298,9 @@ func (item *JiraItem) CreateStory(userMaps []userMap) ClubHouseCreateStory {
 		owners = make([]string, 0)
 	}
 
-	// Map JIRA status to Clubhouse Workflow state
-	// cases break automatically, no fallthrough by default
-	var state int64 = 500000014
-	switch item.Status {
-	    case "Open":
-				// backlog
-				state = 500000008
-	    case "In Progress":
-				// in development
-				state = 500000006
-			case "Blocked":
-				// blocked
-				state = 500000030
-	    case "Code Review":
-	    	// selected
-	    	state = 500000010
-	    case "Ready for QA":
-	    	// ready for qa
-				state = 500000027
-	    case "In QA":
-	    	// in qa
-	    	state = 500000028
-	    case "Accepted":
-	    	// qa passed
-	    	state = 500000031
-	    case "Closed":
-	    	state = 500000011
-	    default:
-	    	// backlog
-				state = 500000008
-    }
+	// Map JIRA status to Clubhouse Workflow state via the configured mapping,
+	// falling back to mc.FallbackState for anything unmapped.
+	state := mc.WorkflowStateFor(item.Status)
 
     requestor := MapUser(userMaps, item.Reporter.Username)
     // _, requestor := GetUserInfo(userMaps, item.Reporter.Username)
@@ -235,33 +311,71 @@ func (item *JiraItem) CreateStory(userMaps []userMap) ClubHouseCreateStory {
     }
 
 		// Set Jira external link
-		jiraLink := "https://jira.yk.wildskymedia.com/browse/"
+		jiraLink := mc.JiraBaseURL
 		jiraLink += item.Key
-		var jiraLinkArray []string
-		jiraLinkArray = append(jiraLinkArray, jiraLink)
+
+		// Append any mapped custom fields with no dedicated target (e.g. Epic
+		// Name) to the description as an appendix, since Clubhouse/Shortcut has
+		// no field for them.
+		description := sanitize.HTML(item.Description)
+		if len(mapped.DescriptionExtras) > 0 {
+			description += "\n\n**Custom Fields**\n"
+			for _, extra := range mapped.DescriptionExtras {
+				description += "- " + extra + "\n"
+			}
+		}
+
+		// Derive StartedAt/CompletedAt from the changelog when it's
+		// available (live Jira REST ingestion), since it reflects when the
+		// item actually moved, instead of the ResolvedAtString +/- 1 day
+		// heuristic below. Also append a synthesized history section so the
+		// audit trail isn't lost when only the final Status is examined.
+		changelog := item.SummarizeChangelog(mc)
+		description += changelog.HistoryText
+
+		completedAt := parseItemTimestamp(item.ResolvedAtString, item.FromLiveAPI, 0)
+		startedAt := parseItemTimestamp(item.ResolvedAtString, item.FromLiveAPI, -1)
+		if changelog.StartedAt != nil {
+			startedAt = *changelog.StartedAt
+		}
+		if changelog.CompletedAt != nil {
+			completedAt = *changelog.CompletedAt
+		}
 
     fmt.Printf("%s: JIRA Assignee: %s | Project: %d | Status: %s\n\n", item.Key, item.Assignee.Username, item.Status)
 
+		// Migrate attachments by downloading them from Jira and re-uploading
+		// them to Shortcut, if an AttachmentMigrator was configured.
+		var fileIDs []int64
+		if migrator != nil {
+			var err error
+			fileIDs, err = migrator.Migrate(item)
+			if err != nil {
+				fmt.Println("[AttachmentMigrator] failed to migrate attachments for", item.Key, ":", err)
+			}
+		}
+
 	return ClubHouseCreateStory{
 		Comments:    	comments,
-		CreatedAt:   	ParseJiraTimeStamp(item.CreatedAtString),
-		UpdatedAt:   	ParseJiraTimeStamp(item.UpdatedAtString),
-		CompletedAt:   	ParseJiraTimeStamp(item.ResolvedAtString),
-		StartedAt:   	ParseJiraTimeStampWithDelta(item.ResolvedAtString, -1),
-		Description: 	sanitize.HTML(item.Description),
+		CreatedAt:   	parseItemTimestamp(item.CreatedAtString, item.FromLiveAPI, 0),
+		UpdatedAt:   	parseItemTimestamp(item.UpdatedAtString, item.FromLiveAPI, 0),
+		CompletedAt:   	completedAt,
+		StartedAt:   	startedAt,
+		Description: 	description,
 		Labels:      	labels,
 		Name:        	sanitize.HTML(item.Summary),
 		// ProjectID:   	int64(projectID),
-		StoryType:   	item.GetClubhouseType(),
+		StoryType:   	item.GetClubhouseType(mc),
 		key:         	item.Key,
-		epicLink:    	item.GetEpicLink(),
+		epicLink:    	mapped.EpicLink,
 		WorkflowState:	state,
 		OwnerIDs:		owners,
 		RequestedBy:	requestor,
-		Estimate: 		item.GetEstimate(),
-		GroupID: 			"62132e09-7216-4f8c-860d-9907f4a243bc", // Hardcoding Engineering team ID
-		ExternalID:		item.Key, // Shortcut allows setting an external id 
-		ExternalLinks: jiraLinkArray,
+		Estimate: 		mapped.Estimate,
+		GroupID: 			mc.GroupID,
+		ExternalID:		item.Key, // Shortcut allows setting an external id
+		ExternalLinks: []string{jiraLink},
+		FileIDs:		fileIDs,
 	}
 }
 
@@ -304,53 +418,16 @@ func (comment *JiraComment) CreateComment(userMaps []userMap) ClubHouseCreateCom
 
 	return ClubHouseCreateComment{
 		Text:		commentText,
-		CreatedAt:	ParseJiraTimeStamp(comment.CreatedAtString),
+		CreatedAt:	parseItemTimestamp(comment.CreatedAtString, comment.FromLiveAPI, 0),
 		Author: 	author,
 	}
 }
 
-// GetEpicLink returns the Epic Link of a Jira Item.
-func (item *JiraItem) GetEpicLink() string {
-	for _, cf := range item.CustomFields {
-		if cf.FieldName == "Epic Link" {
-			return cf.FieldValues[0]
-		}
-	}
-	return ""
-}
-
-// GetEstimate returns the estimate of a Jira Item.
-func (item *JiraItem) GetEstimate() int64 {
-	for _, cf := range item.CustomFields {
-		if cf.FieldName == "Story Points" {
-			if i, err := strconv.ParseFloat(cf.FieldValues[0], 64); err == nil {
-				return int64(i)
-			}
-			
-		}
-	}
-	return 0
-}
-
-// GetLastSprint returns the latest sprint a Jira Item was in.
-func (item *JiraItem) GetLastSprint() string {
-	for _, cf := range item.CustomFields {
-		if cf.FieldName == "Sprint" && len(cf.FieldValues) > 0 {
-			return cf.FieldValues[len(cf.FieldValues)-1]
-		}
-	}
-	return ""
-}
-
-// GetClubhouseType determines type based on if the Jira item is a bug or not.
-func (item *JiraItem) GetClubhouseType() string {
-	if item.Type == "Bug" {
-		return "bug"
-	} else if item.Type == "Task" {
-		return "chore"
-	} else {
-		return "feature"
-	}
+// GetClubhouseType determines the Clubhouse/Shortcut story type for the Jira
+// item using mc's type_map, falling back to "feature" when the Jira issue
+// type has no entry.
+func (item *JiraItem) GetClubhouseType(mc *MappingConfig) string {
+	return mc.StoryTypeFor(item.Type)
 }
 
 // ParseJiraTimeStamp parses the format in the XML using Go's magical timestamp.
@@ -367,3 +444,32 @@ func ParseJiraTimeStampWithDelta(dateString string, daysToAdd int) time.Time {
 func ParseJiraTimeStamp(dateString string) time.Time {
 	return ParseJiraTimeStampWithDelta(dateString, 0)
 }
+
+// ParseJiraRESTTimestampWithDelta parses the timestamp format used by the
+// Jira REST API (e.g. a changelog history's "created"), which differs from
+// the RSS export's format that ParseJiraTimeStampWithDelta handles, and
+// adds daysToAdd days.
+func ParseJiraRESTTimestampWithDelta(dateString string, daysToAdd int) time.Time {
+	t, err := time.Parse("2006-01-02T15:04:05.000-0700", dateString)
+	if err != nil {
+		return time.Now().AddDate(0, 0, daysToAdd)
+	}
+	return t.AddDate(0, 0, daysToAdd)
+}
+
+// ParseJiraRESTTimestamp parses the timestamp format used by the Jira REST
+// API (e.g. a changelog history's "created"), which differs from the RSS
+// export's format that ParseJiraTimeStamp handles.
+func ParseJiraRESTTimestamp(dateString string) time.Time {
+	return ParseJiraRESTTimestampWithDelta(dateString, 0)
+}
+
+// parseItemTimestamp parses dateString with whichever format matches its
+// source: the live Jira REST API's format when fromLiveAPI is true, or the
+// XML export's RSS format otherwise.
+func parseItemTimestamp(dateString string, fromLiveAPI bool, daysToAdd int) time.Time {
+	if fromLiveAPI {
+		return ParseJiraRESTTimestampWithDelta(dateString, daysToAdd)
+	}
+	return ParseJiraTimeStampWithDelta(dateString, daysToAdd)
+}
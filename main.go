@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func main() {
+	xmlPath := flag.String("xml", "", "path to a Jira XML export (takes priority over -jira-url as the item source)")
+	userMapPath := flag.String("users", "users.json", "path to the JSON file mapping Jira usernames to Clubhouse/Shortcut member IDs")
+	mappingPath := flag.String("mapping", "mapping.yaml", "path to the status/type/custom-field mapping YAML file")
+	outPath := flag.String("out", "", "path to write the generated Clubhouse import JSON (defaults to stdout)")
+
+	liveFlags := RegisterLiveSourceFlags(flag.CommandLine)
+	syncFlags := RegisterSyncFlags(flag.CommandLine)
+
+	flag.Parse()
+
+	mc, err := LoadMappingConfig(*mappingPath)
+	if err != nil {
+		log.Fatalf("loading mapping config: %v", err)
+	}
+
+	userMaps, err := loadUserMaps(*userMapPath)
+	if err != nil {
+		log.Fatalf("loading user map: %v", err)
+	}
+
+	// -xml takes priority over -jira-url as the item source: that lets
+	// -jira-url (plus its auth flags) double as the Jira connection the
+	// AttachmentMigrator below downloads attachments with, even when items
+	// themselves come from an XML export rather than a live search.
+	var items []JiraItem
+	switch {
+	case *xmlPath != "":
+		items, err = loadItemsFromXML(*xmlPath)
+		if err != nil {
+			log.Fatalf("loading Jira XML export: %v", err)
+		}
+	case liveFlags.Enabled():
+		items, err = FetchLiveItems(liveFlags.Config(), *liveFlags.JQL)
+		if err != nil {
+			log.Fatalf("fetching live Jira issues: %v", err)
+		}
+	default:
+		log.Fatal("one of -xml or -jira-url must be supplied")
+	}
+
+	if err := mc.ValidateStatuses(items); err != nil {
+		log.Fatal(err)
+	}
+
+	if *syncFlags.ShortcutAPIToken != "" {
+		knownStateIDs, err := FetchShortcutWorkflowStateIDs(*syncFlags.ShortcutAPIToken)
+		if err != nil {
+			log.Fatalf("validating mapping config against Shortcut: %v", err)
+		}
+		if err := mc.ValidateWorkflowStates(knownStateIDs); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Built whenever Jira auth and a Shortcut token are both configured,
+	// independent of which branch above supplied items: an -xml import can
+	// still have its attachments migrated as long as -jira-url/-jira-auth
+	// point at the Jira instance the XML was exported from.
+	var migrator *AttachmentMigrator
+	if liveFlags.Enabled() && *syncFlags.ShortcutAPIToken != "" {
+		migrator = NewAttachmentMigrator(liveFlags.Config(), *syncFlags.ShortcutAPIToken)
+	}
+
+	shortcut := syncFlags.Client()
+
+	export := JiraExport{Items: items}
+	data := export.GetDataForClubhouse(userMaps, mc, migrator, shortcut)
+
+	if err := syncFlags.Apply(data, shortcut); err != nil {
+		log.Fatalf("applying --sync: %v", err)
+	}
+
+	if syncFlags.Enabled() {
+		return
+	}
+
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		log.Fatalf("marshalling Clubhouse data: %v", err)
+	}
+
+	if *outPath == "" {
+		fmt.Println(string(out))
+		return
+	}
+
+	if err := os.WriteFile(*outPath, out, 0644); err != nil {
+		log.Fatalf("writing %s: %v", *outPath, err)
+	}
+}
+
+// loadItemsFromXML reads and unmarshals a Jira RSS/XML export.
+func loadItemsFromXML(path string) ([]JiraItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var export JiraExport
+	if err := xml.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("unmarshalling %s: %w", path, err)
+	}
+
+	return export.Items, nil
+}
+
+// loadUserMaps reads the Jira-to-Clubhouse user mapping file. A missing
+// file is not an error: the tool simply falls back to unassigned owners.
+func loadUserMaps(path string) ([]userMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var userMaps []userMap
+	if err := json.Unmarshal(data, &userMaps); err != nil {
+		return nil, fmt.Errorf("unmarshalling %s: %w", path, err)
+	}
+
+	return userMaps, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MappingConfig replaces the hardcoded status/type switches and Clubhouse
+// group/Jira URL that used to live in CreateStory. It is loaded once at
+// startup from a YAML file (--mapping mapping.yaml) so the tool can be
+// reused across teams, each with their own workflow, without recompiling.
+//
+// Example:
+//
+//	status_map:
+//	  Open: 500000008
+//	  In Progress: 500000006
+//	type_map:
+//	  Bug: bug
+//	  Task: chore
+//	fallback_state: 500000008
+//	group_id: 62132e09-7216-4f8c-860d-9907f4a243bc
+//	jira_base_url: https://jira.yk.wildskymedia.com/browse/
+//	custom_fields:
+//	  - jira_field: Story Points
+//	    target: estimate
+//	    transform: round
+type MappingConfig struct {
+	StatusMap     map[string]int64  `yaml:"status_map"`
+	TypeMap       map[string]string `yaml:"type_map"`
+	ResolutionMap map[string]string `yaml:"resolution_map"`
+	PriorityMap   map[string]string `yaml:"priority_map"`
+	GroupID       string            `yaml:"group_id"`
+	JiraBaseURL   string            `yaml:"jira_base_url"`
+	FallbackState int64             `yaml:"fallback_state"`
+	CustomFields  []CustomFieldRule `yaml:"custom_fields"`
+
+	// StatusCategories marks which Jira statuses count as "work started"
+	// and "work completed" (StatusCategoryStarted / StatusCategoryDone),
+	// so changelog-driven StartedAt/CompletedAt can be derived without
+	// hardcoding specific status names.
+	StatusCategories map[string]string `yaml:"status_categories"`
+}
+
+// LoadMappingConfig reads and parses a mapping YAML file.
+func LoadMappingConfig(path string) (*MappingConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("mapping: reading %s: %w", path, err)
+	}
+
+	var mc MappingConfig
+	if err := yaml.Unmarshal(data, &mc); err != nil {
+		return nil, fmt.Errorf("mapping: parsing %s: %w", path, err)
+	}
+
+	if mc.FallbackState == 0 {
+		return nil, fmt.Errorf("mapping: %s must set fallback_state", path)
+	}
+	if mc.GroupID == "" {
+		return nil, fmt.Errorf("mapping: %s must set group_id", path)
+	}
+
+	return &mc, nil
+}
+
+// WorkflowStateFor returns the Clubhouse/Shortcut workflow-state ID mapped
+// to a Jira status, falling back to FallbackState when the status has no
+// entry.
+func (mc *MappingConfig) WorkflowStateFor(status string) int64 {
+	if state, ok := mc.StatusMap[status]; ok {
+		return state
+	}
+	return mc.FallbackState
+}
+
+// StoryTypeFor returns the Clubhouse/Shortcut story type mapped to a Jira
+// issue type, falling back to "feature" to match the prior hardcoded
+// default.
+func (mc *MappingConfig) StoryTypeFor(issueType string) string {
+	if t, ok := mc.TypeMap[issueType]; ok {
+		return t
+	}
+	return "feature"
+}
+
+// ValidateStatuses checks that every Jira status present in items has a
+// status_map entry, returning a single error listing all of the unmapped
+// statuses it found.
+func (mc *MappingConfig) ValidateStatuses(items []JiraItem) error {
+	missing := map[string]bool{}
+	for _, item := range items {
+		if _, ok := mc.StatusMap[item.Status]; !ok {
+			missing[item.Status] = true
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	statuses := make([]string, 0, len(missing))
+	for status := range missing {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	return fmt.Errorf("mapping: no status_map entry for Jira status(es): %s", strings.Join(statuses, ", "))
+}
+
+// ValidateWorkflowStates checks that FallbackState and every value in
+// StatusMap refers to a workflow-state ID that actually exists in
+// Shortcut, returning a single error listing the unknown ones.
+func (mc *MappingConfig) ValidateWorkflowStates(knownStateIDs map[int64]bool) error {
+	var unknown []int64
+	seen := map[int64]bool{}
+
+	check := func(id int64) {
+		if seen[id] || knownStateIDs[id] {
+			return
+		}
+		seen[id] = true
+		unknown = append(unknown, id)
+	}
+
+	check(mc.FallbackState)
+	for _, id := range mc.StatusMap {
+		check(id)
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i] < unknown[j] })
+
+	ids := make([]string, len(unknown))
+	for i, id := range unknown {
+		ids[i] = fmt.Sprintf("%d", id)
+	}
+
+	return fmt.Errorf("mapping: status_map references unknown Shortcut workflow state(s): %s", strings.Join(ids, ", "))
+}
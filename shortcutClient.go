@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const shortcutAPIBase = "https://api.app.shortcut.com/api/v3"
+
+// ShortcutClient talks to the Shortcut REST API on behalf of a single API
+// token. It backs --dry-run (looking up existing stories by ExternalID) and
+// --sync (updating the ones that changed).
+type ShortcutClient struct {
+	apiToken   string
+	httpClient *http.Client
+
+	// DryRun, when true, tells GetDataForClubhouse to only compute diffs
+	// against Shortcut, never to hand back stories/updates that would
+	// create or mutate anything there.
+	DryRun bool
+}
+
+// NewShortcutClient builds a ShortcutClient authenticated with apiToken.
+func NewShortcutClient(apiToken string) *ShortcutClient {
+	return &ShortcutClient{apiToken: apiToken, httpClient: &http.Client{}}
+}
+
+// ShortcutStory is the subset of a Shortcut story that dry-run diffing and
+// sync care about.
+type ShortcutStory struct {
+	ID              int64    `json:"id"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	WorkflowStateID int64    `json:"workflow_state_id"`
+	ExternalID      string   `json:"external_id"`
+	Labels          []string `json:"-"`
+}
+
+type shortcutStoryLabel struct {
+	Name string `json:"name"`
+}
+
+type shortcutRawStory struct {
+	ID              int64                `json:"id"`
+	Name            string               `json:"name"`
+	Description     string               `json:"description"`
+	WorkflowStateID int64                `json:"workflow_state_id"`
+	ExternalID      string               `json:"external_id"`
+	Labels          []shortcutStoryLabel `json:"labels"`
+}
+
+// FindStoryByExternalID looks up the Shortcut story whose external_id
+// matches externalID (the code already sets ExternalID: item.Key when
+// creating stories). It returns a nil *ShortcutStory, not an error, when no
+// story matches, since "not found yet" is the expected case on first
+// import.
+func (c *ShortcutClient) FindStoryByExternalID(externalID string) (*ShortcutStory, error) {
+	req, err := http.NewRequest(http.MethodGet, shortcutAPIBase+"/search/stories", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := url.Values{}
+	q.Set("query", fmt.Sprintf("external_id:%q", externalID))
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Shortcut-Token", c.apiToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("shortcut: searching for external_id %s: %w", externalID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("shortcut: search returned %s", resp.Status)
+	}
+
+	var result struct {
+		Data []shortcutRawStory `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("shortcut: decoding search response: %w", err)
+	}
+
+	for _, raw := range result.Data {
+		if raw.ExternalID != externalID {
+			continue
+		}
+		return rawStoryToShortcutStory(raw), nil
+	}
+
+	return nil, nil
+}
+
+// UpdateStory sends a PUT for the Shortcut story identified by update.ID,
+// carrying whichever fields update sets.
+func (c *ShortcutClient) UpdateStory(update ClubHouseUpdateStory) error {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/stories/%d", shortcutAPIBase, update.ID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Shortcut-Token", c.apiToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("shortcut: updating story %d: %w", update.ID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("shortcut: updating story %d returned %s", update.ID, resp.Status)
+	}
+
+	return nil
+}
+
+func rawStoryToShortcutStory(raw shortcutRawStory) *ShortcutStory {
+	labels := make([]string, len(raw.Labels))
+	for i, l := range raw.Labels {
+		labels[i] = l.Name
+	}
+
+	return &ShortcutStory{
+		ID:              raw.ID,
+		Name:            raw.Name,
+		Description:     raw.Description,
+		WorkflowStateID: raw.WorkflowStateID,
+		ExternalID:      raw.ExternalID,
+		Labels:          labels,
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const shortcutWorkflowsURL = "https://api.app.shortcut.com/api/v3/workflows"
+
+type shortcutWorkflowState struct {
+	ID int64 `json:"id"`
+}
+
+type shortcutWorkflow struct {
+	States []shortcutWorkflowState `json:"states"`
+}
+
+// FetchShortcutWorkflowStateIDs calls Shortcut's /workflows endpoint and
+// returns the set of every workflow-state ID it knows about, so a
+// MappingConfig can be validated against it before import runs.
+func FetchShortcutWorkflowStateIDs(apiToken string) (map[int64]bool, error) {
+	req, err := http.NewRequest(http.MethodGet, shortcutWorkflowsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Shortcut-Token", apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("shortcut: fetching workflows: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("shortcut: /workflows returned %s", resp.Status)
+	}
+
+	var workflows []shortcutWorkflow
+	if err := json.NewDecoder(resp.Body).Decode(&workflows); err != nil {
+		return nil, fmt.Errorf("shortcut: decoding /workflows response: %w", err)
+	}
+
+	ids := make(map[int64]bool)
+	for _, workflow := range workflows {
+		for _, state := range workflow.States {
+			ids[state.ID] = true
+		}
+	}
+
+	return ids, nil
+}
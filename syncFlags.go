@@ -0,0 +1,58 @@
+package main
+
+import "flag"
+
+// SyncFlags holds the CLI flags for --dry-run and --sync mode: re-running
+// the tool against tickets that were already imported, using the
+// external_id the first import set, instead of creating duplicates.
+type SyncFlags struct {
+	DryRun           *bool
+	Sync             *bool
+	ShortcutAPIToken *string
+}
+
+// RegisterSyncFlags adds the --dry-run/--sync flag set to fs.
+func RegisterSyncFlags(fs *flag.FlagSet) *SyncFlags {
+	return &SyncFlags{
+		DryRun:           fs.Bool("dry-run", false, "print a diff of what would be created/updated/skipped, without changing anything in Shortcut"),
+		Sync:             fs.Bool("sync", false, "update existing Shortcut stories (matched by external_id) instead of creating duplicates"),
+		ShortcutAPIToken: fs.String("shortcut-token", "", "Shortcut API token, required for --dry-run or --sync"),
+	}
+}
+
+// Enabled reports whether either --dry-run or --sync was selected, meaning
+// GetDataForClubhouse needs a ShortcutClient to diff against.
+func (f *SyncFlags) Enabled() bool {
+	return (f.DryRun != nil && *f.DryRun) || (f.Sync != nil && *f.Sync)
+}
+
+// Client builds the ShortcutClient these flags call for, or nil if neither
+// --dry-run nor --sync was selected.
+func (f *SyncFlags) Client() *ShortcutClient {
+	if !f.Enabled() {
+		return nil
+	}
+
+	client := NewShortcutClient(*f.ShortcutAPIToken)
+	client.DryRun = f.DryRun != nil && *f.DryRun
+
+	return client
+}
+
+// Apply runs the mode these flags selected against data: printing the diff
+// for --dry-run, or issuing the story update requests for --sync.
+func (f *SyncFlags) Apply(data ClubHouseData, shortcut *ShortcutClient) error {
+	if f.DryRun != nil && *f.DryRun {
+		PrintDiffs(data.Diffs)
+	}
+
+	if f.Sync != nil && *f.Sync {
+		for _, update := range data.Updates {
+			if err := shortcut.UpdateStory(update); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ClubHouseUpdateStory is an update to an existing Shortcut story, sent in
+// --sync mode instead of creating a duplicate. Only the fields that are set
+// are sent; NewUpdateStory sets all of them, whether or not each one
+// differs from the live story.
+type ClubHouseUpdateStory struct {
+	ID              int64                   `json:"-"`
+	Name            *string                 `json:"name,omitempty"`
+	Description     *string                 `json:"description,omitempty"`
+	WorkflowStateID *int64                  `json:"workflow_state_id,omitempty"`
+	Labels          []ClubHouseCreateLabel  `json:"labels,omitempty"`
+}
+
+// StoryDiffAction is what would happen to a story on import: it's new, it
+// changed, or it's identical to what's already in Shortcut.
+type StoryDiffAction string
+
+const (
+	StoryDiffCreate StoryDiffAction = "create"
+	StoryDiffUpdate StoryDiffAction = "update"
+	StoryDiffSkip   StoryDiffAction = "skip"
+)
+
+// StoryDiff summarizes what --dry-run would do for one Jira item.
+type StoryDiff struct {
+	Key     string
+	Action  StoryDiffAction
+	Changes []string
+}
+
+// DiffStory compares an existing Shortcut story against the story
+// CreateStory would produce, returning what --sync would need to change.
+// existing is nil when no Shortcut story has this external_id yet.
+func DiffStory(existing *ShortcutStory, desired ClubHouseCreateStory) StoryDiff {
+	if existing == nil {
+		return StoryDiff{Key: desired.ExternalID, Action: StoryDiffCreate}
+	}
+
+	var changes []string
+
+	if existing.Name != desired.Name {
+		changes = append(changes, fmt.Sprintf("name: %q -> %q", existing.Name, desired.Name))
+	}
+	if existing.Description != desired.Description {
+		changes = append(changes, "description changed")
+	}
+	if existing.WorkflowStateID != desired.WorkflowState {
+		changes = append(changes, fmt.Sprintf("workflow_state_id: %d -> %d", existing.WorkflowStateID, desired.WorkflowState))
+	}
+	if !sameLabels(existing.Labels, desired.Labels) {
+		changes = append(changes, "labels changed")
+	}
+
+	if len(changes) == 0 {
+		return StoryDiff{Key: desired.ExternalID, Action: StoryDiffSkip}
+	}
+
+	return StoryDiff{Key: desired.ExternalID, Action: StoryDiffUpdate, Changes: changes}
+}
+
+func sameLabels(existing []string, desired []ClubHouseCreateLabel) bool {
+	a := make([]string, len(existing))
+	copy(a, existing)
+
+	b := make([]string, len(desired))
+	for i, l := range desired {
+		b[i] = l.Name
+	}
+
+	sort.Strings(a)
+	sort.Strings(b)
+
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NewUpdateStory builds the update payload for taking an existing Shortcut
+// story to the state desired describes. Name, Description, WorkflowStateID
+// and Labels are always all set, not only the ones DiffStory found changed.
+func NewUpdateStory(existingID int64, desired ClubHouseCreateStory) ClubHouseUpdateStory {
+	name := desired.Name
+	description := desired.Description
+	workflowState := desired.WorkflowState
+
+	return ClubHouseUpdateStory{
+		ID:              existingID,
+		Name:            &name,
+		Description:     &description,
+		WorkflowStateID: &workflowState,
+		Labels:          desired.Labels,
+	}
+}
+
+// ANSI color codes for --dry-run output. No third-party dependency is
+// pulled in just to colorize a few lines.
+const (
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiGray   = "\033[90m"
+	ansiReset  = "\033[0m"
+)
+
+// PrintDiffs prints a --dry-run summary: one colored line per story,
+// green for creates, yellow for updates (with what changed), gray for
+// unchanged stories that will be skipped.
+func PrintDiffs(diffs []StoryDiff) {
+	for _, diff := range diffs {
+		switch diff.Action {
+		case StoryDiffCreate:
+			fmt.Printf("%s+ %s: create%s\n", ansiGreen, diff.Key, ansiReset)
+		case StoryDiffUpdate:
+			fmt.Printf("%s~ %s: update (%s)%s\n", ansiYellow, diff.Key, strings.Join(diff.Changes, "; "), ansiReset)
+		case StoryDiffSkip:
+			fmt.Printf("%s= %s: unchanged%s\n", ansiGray, diff.Key, ansiReset)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import "testing"
+
+func TestDiffStory(t *testing.T) {
+	cases := []struct {
+		name     string
+		existing *ShortcutStory
+		desired  ClubHouseCreateStory
+		action   StoryDiffAction
+		changes  int
+	}{
+		{
+			name:     "no existing story creates",
+			existing: nil,
+			desired:  ClubHouseCreateStory{ExternalID: "PROJ-1", Name: "A story"},
+			action:   StoryDiffCreate,
+		},
+		{
+			name: "identical story skips",
+			existing: &ShortcutStory{
+				Name:            "A story",
+				Description:     "desc",
+				WorkflowStateID: 500,
+				Labels:          []string{"jira-PROJ", "PROJ-1"},
+			},
+			desired: ClubHouseCreateStory{
+				ExternalID:    "PROJ-1",
+				Name:          "A story",
+				Description:   "desc",
+				WorkflowState: 500,
+				Labels: []ClubHouseCreateLabel{
+					{Name: "jira-PROJ"},
+					{Name: "PROJ-1"},
+				},
+			},
+			action: StoryDiffSkip,
+		},
+		{
+			name: "identical story skips regardless of label order",
+			existing: &ShortcutStory{
+				Name:            "A story",
+				WorkflowStateID: 500,
+				Labels:          []string{"PROJ-1", "jira-PROJ"},
+			},
+			desired: ClubHouseCreateStory{
+				ExternalID:    "PROJ-1",
+				Name:          "A story",
+				WorkflowState: 500,
+				Labels: []ClubHouseCreateLabel{
+					{Name: "jira-PROJ"},
+					{Name: "PROJ-1"},
+				},
+			},
+			action: StoryDiffSkip,
+		},
+		{
+			name: "changed name updates",
+			existing: &ShortcutStory{
+				Name:            "Old name",
+				WorkflowStateID: 500,
+			},
+			desired: ClubHouseCreateStory{
+				ExternalID:    "PROJ-1",
+				Name:          "New name",
+				WorkflowState: 500,
+			},
+			action:  StoryDiffUpdate,
+			changes: 1,
+		},
+		{
+			name: "changed status, description and labels updates with all changes reported",
+			existing: &ShortcutStory{
+				Name:            "A story",
+				Description:     "old desc",
+				WorkflowStateID: 500,
+				Labels:          []string{"jira-PROJ"},
+			},
+			desired: ClubHouseCreateStory{
+				ExternalID:    "PROJ-1",
+				Name:          "A story",
+				Description:   "new desc",
+				WorkflowState: 600,
+				Labels: []ClubHouseCreateLabel{
+					{Name: "jira-PROJ"},
+					{Name: "PROJ-1"},
+				},
+			},
+			action:  StoryDiffUpdate,
+			changes: 3,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff := DiffStory(tc.existing, tc.desired)
+
+			if diff.Action != tc.action {
+				t.Errorf("Action = %q, want %q", diff.Action, tc.action)
+			}
+			if diff.Key != tc.desired.ExternalID {
+				t.Errorf("Key = %q, want %q", diff.Key, tc.desired.ExternalID)
+			}
+			if len(diff.Changes) != tc.changes {
+				t.Errorf("len(Changes) = %d, want %d (changes: %v)", len(diff.Changes), tc.changes, diff.Changes)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package main
+
+// userMap links one person's Jira username to their Clubhouse/Shortcut
+// project and member IDs, so JiraItems can be assigned to the right owner.
+type userMap struct {
+	JiraUsername string `json:"jira_username"`
+	CHProjectID  int    `json:"ch_project_id"`
+	CHID         string `json:"ch_id"`
+}